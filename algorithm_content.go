@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/jemgunay/film-recommend/tmdb"
+)
+
+// likedThreshold is the minimum rating a watched film needs to count towards
+// a user's genre profile.
+const likedThreshold = 3.0
+
+// contentBasedAlgorithm scores unwatched films by genre overlap with the
+// films a user rated highly, using the TMDb-enriched metadata rather than
+// other users' ratings. This gives new users (or users with few ratings
+// neighbours) a useful signal that itemcf/svd can't provide on their own.
+type contentBasedAlgorithm struct {
+	mu       sync.RWMutex
+	metadata map[int]tmdb.Metadata // filmID -> enriched metadata
+}
+
+func (a *contentBasedAlgorithm) Name() string {
+	return "content"
+}
+
+// setMetadata replaces the cached film metadata used to score films. The
+// Recommender refreshes this from the latest TMDb-enriched data before each
+// content/blend scoring pass.
+func (a *contentBasedAlgorithm) setMetadata(metadata map[int]tmdb.Metadata) {
+	a.mu.Lock()
+	a.metadata = metadata
+	a.mu.Unlock()
+}
+
+// Score builds a genre weight profile from userID's liked films, then scores
+// every unwatched film with metadata by its overlap with that profile.
+func (a *contentBasedAlgorithm) Score(watched map[string]map[int]float64, userID int) (map[int]float64, error) {
+	a.mu.RLock()
+	metadata := a.metadata
+	a.mu.RUnlock()
+
+	liked := watched[fmt.Sprint(userID)]
+
+	genreWeight := make(map[string]float64)
+	for filmID, rating := range liked {
+		if rating < likedThreshold {
+			continue
+		}
+		meta, ok := metadata[filmID]
+		if !ok {
+			continue
+		}
+		for _, genre := range meta.Genres {
+			genreWeight[genre] += rating
+		}
+	}
+
+	scores := make(map[int]float64, len(metadata))
+	for filmID, meta := range metadata {
+		if _, seen := liked[filmID]; seen {
+			continue
+		}
+
+		var score float64
+		for _, genre := range meta.Genres {
+			score += genreWeight[genre]
+		}
+		if score > 0 {
+			scores[filmID] = score
+		}
+	}
+
+	return scores, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/jemgunay/film-recommend/tmdb"
+)
+
+func TestContentBasedAlgorithm_Score_PrefersOverlappingGenres(t *testing.T) {
+	alg := &contentBasedAlgorithm{}
+	alg.setMetadata(map[int]tmdb.Metadata{
+		10: {Genres: []string{"sci-fi", "action"}},
+		11: {Genres: []string{"sci-fi"}},
+		12: {Genres: []string{"romance"}},
+	})
+
+	watched := map[string]map[int]float64{
+		"1": {10: 5},
+	}
+
+	scores, err := alg.Score(watched, 1)
+	if err != nil {
+		t.Fatalf("Score returned error: %v", err)
+	}
+
+	if _, watched := scores[10]; watched {
+		t.Errorf("Score included film 10, which user 1 has already watched")
+	}
+	if scores[11] <= scores[12] {
+		t.Errorf("Score(11)=%v should be greater than Score(12)=%v, since film 11 shares a genre with the watched film", scores[11], scores[12])
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import "fmt"
+
+// averageRatingAlgorithm scores unwatched films by their mean rating across
+// all users. This is the original recommendation approach, kept as the
+// "default" algorithm and as one input to blend mode.
+type averageRatingAlgorithm struct{}
+
+func (a *averageRatingAlgorithm) Name() string {
+	return "default"
+}
+
+func (a *averageRatingAlgorithm) Score(watched map[string]map[int]float64, userID int) (map[int]float64, error) {
+	sums := make(map[int]float64)
+	counts := make(map[int]int)
+
+	userIDStr := fmt.Sprint(userID)
+	alreadyWatched := watched[userIDStr]
+
+	for _, films := range watched {
+		for filmID, rating := range films {
+			sums[filmID] += rating
+			counts[filmID]++
+		}
+	}
+
+	scores := make(map[int]float64, len(sums))
+	for filmID, sum := range sums {
+		if _, seen := alreadyWatched[filmID]; seen {
+			continue
+		}
+		scores[filmID] = sum / float64(counts[filmID])
+	}
+
+	return scores, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// itemCFAlgorithm scores unwatched films using item-item collaborative
+// filtering: a film's predicted rating is the similarity-weighted average
+// rating of the films the user has already watched.
+type itemCFAlgorithm struct{}
+
+func (a *itemCFAlgorithm) Name() string {
+	return "itemcf"
+}
+
+func (a *itemCFAlgorithm) Score(watched map[string]map[int]float64, userID int) (map[int]float64, error) {
+	userIDStr := fmt.Sprint(userID)
+	alreadyWatched := watched[userIDStr]
+	if len(alreadyWatched) == 0 {
+		return map[int]float64{}, nil
+	}
+
+	// build a per-film vector of ratings across users, keyed by userID
+	filmRatings := make(map[int]map[string]float64)
+	for otherUserID, films := range watched {
+		for filmID, rating := range films {
+			if _, ok := filmRatings[filmID]; !ok {
+				filmRatings[filmID] = make(map[string]float64)
+			}
+			filmRatings[filmID][otherUserID] = rating
+		}
+	}
+
+	weightedSum := make(map[int]float64)
+	similaritySum := make(map[int]float64)
+
+	for candidateID, candidateVec := range filmRatings {
+		if _, seen := alreadyWatched[candidateID]; seen {
+			continue
+		}
+
+		for watchedID, rating := range alreadyWatched {
+			sim := cosineSimilarity(candidateVec, filmRatings[watchedID])
+			if sim <= 0 {
+				continue
+			}
+			weightedSum[candidateID] += sim * rating
+			similaritySum[candidateID] += sim
+		}
+	}
+
+	scores := make(map[int]float64, len(weightedSum))
+	for filmID, sum := range weightedSum {
+		if similaritySum[filmID] == 0 {
+			continue
+		}
+		scores[filmID] = sum / similaritySum[filmID]
+	}
+
+	return scores, nil
+}
+
+// cosineSimilarity computes the cosine similarity of two sparse rating
+// vectors keyed by user ID.
+func cosineSimilarity(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+
+	for userID, ratingA := range a {
+		normA += ratingA * ratingA
+		if ratingB, ok := b[userID]; ok {
+			dot += ratingA * ratingB
+		}
+	}
+	for _, ratingB := range b {
+		normB += ratingB * ratingB
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// floatEqualTolerance accounts for the rounding error inherent in the
+// cosine similarity calculation's sqrt/division chain.
+const floatEqualTolerance = 1e-9
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b map[string]float64
+		want float64
+	}{
+		{
+			name: "identical vectors",
+			a:    map[string]float64{"1": 2, "2": 4},
+			b:    map[string]float64{"1": 2, "2": 4},
+			want: 1,
+		},
+		{
+			name: "disjoint users",
+			a:    map[string]float64{"1": 5},
+			b:    map[string]float64{"2": 5},
+			want: 0,
+		},
+		{
+			name: "empty vector",
+			a:    map[string]float64{},
+			b:    map[string]float64{"1": 5},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cosineSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > floatEqualTolerance {
+				t.Errorf("cosineSimilarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestItemCFAlgorithm_Score_ExcludesWatchedFilms(t *testing.T) {
+	watched := map[string]map[int]float64{
+		"1": {10: 5, 11: 4},
+		"2": {10: 5, 12: 3},
+	}
+
+	alg := &itemCFAlgorithm{}
+	scores, err := alg.Score(watched, 1)
+	if err != nil {
+		t.Fatalf("Score returned error: %v", err)
+	}
+
+	if _, watched := scores[10]; watched {
+		t.Errorf("Score included film 10, which user 1 has already watched")
+	}
+	if _, ok := scores[12]; !ok {
+		t.Errorf("Score did not include unwatched film 12")
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// svdAlgorithm predicts ratings from a rank-k truncated SVD of the
+// mean-centered user x film ratings matrix, computed via randomized SVD.
+type svdAlgorithm struct {
+	rank         int
+	oversampling int
+	modelPath    string // where the factorised U, Sigma, V matrices are persisted between rebuilds
+
+	mu    sync.RWMutex
+	users []string           // row index -> userID string
+	films []int              // column index -> filmID
+	means map[string]float64 // per-user mean rating, subtracted before factorisation
+	u     *mat.Dense         // n x k
+	sigma []float64          // k
+	v     *mat.Dense         // m x k
+}
+
+func (a *svdAlgorithm) Name() string {
+	return "svd"
+}
+
+// Score predicts a rating for every unwatched film using the cached
+// factorisation, rebuilding it first if it hasn't been computed yet.
+func (a *svdAlgorithm) Score(watched map[string]map[int]float64, userID int) (map[int]float64, error) {
+	a.mu.RLock()
+	ready := a.u != nil
+	a.mu.RUnlock()
+
+	if !ready {
+		if err := a.rebuildSafely(watched); err != nil {
+			return nil, fmt.Errorf("build SVD model: %w", err)
+		}
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	userIDStr := fmt.Sprint(userID)
+	userRow := -1
+	for i, id := range a.users {
+		if id == userIDStr {
+			userRow = i
+			break
+		}
+	}
+	if userRow == -1 {
+		return map[int]float64{}, nil
+	}
+
+	alreadyWatched := watched[userIDStr]
+	mean := a.means[userIDStr]
+
+	scores := make(map[int]float64, len(a.films))
+	for col, filmID := range a.films {
+		if _, seen := alreadyWatched[filmID]; seen {
+			continue
+		}
+
+		var predicted float64
+		for k := range a.sigma {
+			predicted += a.u.At(userRow, k) * a.sigma[k] * a.v.At(col, k)
+		}
+		scores[filmID] = mean + predicted
+	}
+
+	return scores, nil
+}
+
+// rebuildSafely runs Rebuild, recovering from any panic in the underlying
+// matrix code (e.g. an unexpected shape) so a request-handling goroutine
+// computing the model on demand can never take the connection down with it -
+// the caller still gets back a normal error it can log and report via the
+// chunk0-3 structured-error contract.
+func (a *svdAlgorithm) rebuildSafely(watched map[string]map[int]float64) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic building SVD model: %v", r)
+		}
+	}()
+
+	return a.Rebuild(watched)
+}
+
+// hasAnyRatings reports whether watched contains at least one rating, i.e.
+// whether there's enough data to build a non-empty ratings matrix from.
+func hasAnyRatings(watched map[string]map[int]float64) bool {
+	for _, ratings := range watched {
+		if len(ratings) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Rebuild recomputes the rank-k factorisation from the current ratings data
+// using randomized SVD, and persists it to disk.
+func (a *svdAlgorithm) Rebuild(watched map[string]map[int]float64) error {
+	if !hasAnyRatings(watched) {
+		return fmt.Errorf("no ratings data to build an SVD model from")
+	}
+
+	users, films, matrix, means := buildCenteredMatrix(watched)
+
+	u, sigma, v, err := randomizedSVD(matrix, a.rank, a.oversampling)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.users, a.films, a.means = users, films, means
+	a.u, a.sigma, a.v = u, sigma, v
+	a.mu.Unlock()
+
+	return a.save()
+}
+
+// buildCenteredMatrix builds a dense users x films ratings matrix with each
+// user's ratings mean-centered, along with the index mappings needed to
+// translate back to user/film IDs.
+func buildCenteredMatrix(watched map[string]map[int]float64) (users []string, films []int, matrix *mat.Dense, means map[string]float64) {
+	filmSet := make(map[int]struct{})
+	for _, ratings := range watched {
+		for filmID := range ratings {
+			filmSet[filmID] = struct{}{}
+		}
+	}
+
+	for userID := range watched {
+		users = append(users, userID)
+	}
+	for filmID := range filmSet {
+		films = append(films, filmID)
+	}
+
+	filmCol := make(map[int]int, len(films))
+	for i, filmID := range films {
+		filmCol[filmID] = i
+	}
+
+	means = make(map[string]float64, len(users))
+	matrix = mat.NewDense(len(users), len(films), nil)
+
+	for row, userID := range users {
+		ratings := watched[userID]
+
+		var sum float64
+		for _, rating := range ratings {
+			sum += rating
+		}
+		mean := 0.0
+		if len(ratings) > 0 {
+			mean = sum / float64(len(ratings))
+		}
+		means[userID] = mean
+
+		for filmID, rating := range ratings {
+			matrix.Set(row, filmCol[filmID], rating-mean)
+		}
+	}
+
+	return users, films, matrix, means
+}
+
+// minInt returns the smallest of the given values.
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// randomizedSVD computes an approximate rank-k truncated SVD of A using the
+// Halko/Martinsson/Tropp randomized range-finder algorithm: draw a Gaussian
+// test matrix Omega of size n x (k+oversampling), form Y = A*Omega,
+// QR-decompose Y = Q*R, compute the small matrix B = Q^T*A, take its dense
+// SVD, and project the left singular vectors back with U = Q*Uhat.
+func randomizedSVD(a *mat.Dense, rank, oversampling int) (u *mat.Dense, sigma []float64, v *mat.Dense, err error) {
+	rows, cols := a.Dims()
+
+	sketchSize := minInt(rank+oversampling, rows, cols)
+	if sketchSize < 1 {
+		return nil, nil, nil, fmt.Errorf("matrix too small to factorise")
+	}
+
+	omega := mat.NewDense(cols, sketchSize, nil)
+	dist := distuv.Normal{Mu: 0, Sigma: 1}
+	for i := 0; i < cols; i++ {
+		for j := 0; j < sketchSize; j++ {
+			omega.Set(i, j, dist.Rand())
+		}
+	}
+
+	var y mat.Dense
+	y.Mul(a, omega)
+
+	var qr mat.QR
+	qr.Factorize(&y)
+	var q mat.Dense
+	qr.QTo(&q)
+	q = *(q.Slice(0, rows, 0, sketchSize).(*mat.Dense))
+
+	var b mat.Dense
+	b.Mul(q.T(), a)
+
+	var svd mat.SVD
+	if ok := svd.Factorize(&b, mat.SVDThin); !ok {
+		return nil, nil, nil, fmt.Errorf("SVD factorisation failed")
+	}
+
+	k := minInt(rank, sketchSize)
+
+	var uHat mat.Dense
+	var vFull mat.Dense
+	svd.UTo(&uHat)
+	svd.VTo(&vFull)
+	fullSigma := svd.Values(nil)
+
+	sigma = fullSigma[:k]
+
+	var uProjected mat.Dense
+	uProjected.Mul(&q, uHat.Slice(0, sketchSize, 0, k))
+
+	u = mat.DenseCopyOf(&uProjected)
+	v = mat.DenseCopyOf(vFull.Slice(0, cols, 0, k))
+
+	return u, sigma, v, nil
+}
+
+// svdModel is the on-disk representation of a factorised model.
+type svdModel struct {
+	Users []string
+	Films []int
+	Means map[string]float64
+	U     []float64
+	URows int
+	Sigma []float64
+	V     []float64
+	VRows int
+}
+
+func (a *svdAlgorithm) save() error {
+	f, err := os.Create(a.modelPath)
+	if err != nil {
+		return fmt.Errorf("create SVD model file: %w", err)
+	}
+	defer f.Close()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	uRows, _ := a.u.Dims()
+	vRows, _ := a.v.Dims()
+
+	model := svdModel{
+		Users: a.users,
+		Films: a.films,
+		Means: a.means,
+		U:     a.u.RawMatrix().Data,
+		URows: uRows,
+		Sigma: a.sigma,
+		V:     a.v.RawMatrix().Data,
+		VRows: vRows,
+	}
+
+	return gob.NewEncoder(f).Encode(model)
+}
+
+// load restores a previously persisted model from disk, if one exists.
+func (a *svdAlgorithm) load() error {
+	f, err := os.Open(a.modelPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var model svdModel
+	if err := gob.NewDecoder(f).Decode(&model); err != nil {
+		return err
+	}
+
+	k := len(model.Sigma)
+
+	a.mu.Lock()
+	a.users = model.Users
+	a.films = model.Films
+	a.means = model.Means
+	a.sigma = model.Sigma
+	a.u = mat.NewDense(model.URows, k, model.U)
+	a.v = mat.NewDense(model.VRows, k, model.V)
+	a.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import "testing"
+
+// A household rarely has more users than rank+oversampling (30 by default);
+// randomizedSVD must not panic or divide the matrix incorrectly in that case.
+func TestRandomizedSVD_FewerUsersThanSketchSize(t *testing.T) {
+	watched := map[string]map[int]float64{
+		"1": {10: 5, 11: 3},
+		"2": {10: 4, 12: 2},
+	}
+
+	_, films, matrix, _ := buildCenteredMatrix(watched)
+	rows, cols := matrix.Dims()
+	if rows != 2 || cols != len(films) {
+		t.Fatalf("unexpected matrix shape %dx%d", rows, cols)
+	}
+
+	u, sigma, v, err := randomizedSVD(matrix, 20, 10)
+	if err != nil {
+		t.Fatalf("randomizedSVD returned error: %v", err)
+	}
+
+	uRows, uCols := u.Dims()
+	if uRows != rows {
+		t.Errorf("U has %d rows, want %d", uRows, rows)
+	}
+	if uCols != len(sigma) {
+		t.Errorf("U has %d cols, want len(sigma)=%d", uCols, len(sigma))
+	}
+
+	vRows, vCols := v.Dims()
+	if vRows != cols {
+		t.Errorf("V has %d rows, want %d", vRows, cols)
+	}
+	if vCols != len(sigma) {
+		t.Errorf("V has %d cols, want len(sigma)=%d", vCols, len(sigma))
+	}
+}
+
+func TestRandomizedSVD_SingleUser(t *testing.T) {
+	watched := map[string]map[int]float64{
+		"1": {10: 5, 11: 3, 12: 4},
+	}
+
+	_, _, matrix, _ := buildCenteredMatrix(watched)
+
+	if _, _, _, err := randomizedSVD(matrix, 20, 10); err != nil {
+		t.Fatalf("randomizedSVD returned error for single-user matrix: %v", err)
+	}
+}
+
+func TestSVDAlgorithm_Score_EmptyDB(t *testing.T) {
+	alg := &svdAlgorithm{rank: 20, oversampling: 10}
+
+	if _, err := alg.Score(map[string]map[int]float64{}, 1); err == nil {
+		t.Fatal("Score() with no ratings data should return an error, not panic")
+	}
+}
+
+func TestMinInt(t *testing.T) {
+	if got := minInt(5, 2, 9); got != 2 {
+		t.Errorf("minInt(5, 2, 9) = %d, want 2", got)
+	}
+	if got := minInt(3); got != 3 {
+		t.Errorf("minInt(3) = %d, want 3", got)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	accessTokenTTL  = time.Hour
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// Token types, carried in userClaims.TokenType so an access token can't be
+// used where a refresh token is required and vice versa.
+const (
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+)
+
+// userClaims are the JWT claims issued on login, identifying the user, which
+// kind of token this is, and whether they hold admin privileges.
+type userClaims struct {
+	UserID    int    `json:"user_id"`
+	TokenType string `json:"typ"`
+	IsAdmin   bool   `json:"is_admin"`
+	jwt.RegisteredClaims
+}
+
+// issueToken signs a JWT of the given type for userID with the given
+// time-to-live.
+func (s *Server) issueToken(userID int, isAdmin bool, tokenType string, ttl time.Duration) (string, error) {
+	claims := userClaims{
+		UserID:    userID,
+		TokenType: tokenType,
+		IsAdmin:   isAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.cfg.JWTSecret))
+}
+
+// parseToken validates a JWT, checks it is of the expected tokenType, and
+// returns its claims.
+func (s *Server) parseToken(tokenString, tokenType string) (*userClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &userClaims{}, func(t *jwt.Token) (interface{}, error) {
+		return []byte(s.cfg.JWTSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*userClaims)
+	if !ok {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+	if claims.TokenType != tokenType {
+		return nil, fmt.Errorf("wrong token type: want %q, got %q", tokenType, claims.TokenType)
+	}
+
+	return claims, nil
+}
+
+type authContextKey string
+
+const (
+	authUserIDKey  authContextKey = "auth_user_id"
+	authIsAdminKey authContextKey = "auth_is_admin"
+)
+
+// userIDFromContext returns the authenticated user ID set by requireAuth.
+func userIDFromContext(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(authUserIDKey).(int)
+	return id, ok
+}
+
+// isAdminFromContext reports whether requireAuth authenticated the caller as
+// an admin.
+func isAdminFromContext(ctx context.Context) bool {
+	isAdmin, _ := ctx.Value(authIsAdminKey).(bool)
+	return isAdmin
+}
+
+// requireAuth wraps an apiHandler, rejecting requests without a valid
+// "Authorization: Bearer <token>" header and injecting the authenticated
+// user ID and admin flag into the request context.
+func (s *Server) requireAuth(next apiHandler) apiHandler {
+	return func(w http.ResponseWriter, r *http.Request) *APIError {
+		header := r.Header.Get("Authorization")
+		const prefix = "Bearer "
+		if !strings.HasPrefix(header, prefix) {
+			return errUnauthorized("missing bearer token")
+		}
+
+		claims, err := s.parseToken(strings.TrimPrefix(header, prefix), tokenTypeAccess)
+		if err != nil {
+			return errUnauthorized("invalid or expired token")
+		}
+
+		ctx := context.WithValue(r.Context(), authUserIDKey, claims.UserID)
+		ctx = context.WithValue(ctx, authIsAdminKey, claims.IsAdmin)
+		return next(w, r.WithContext(ctx))
+	}
+}
+
+// requireAdmin wraps an apiHandler that has already passed through
+// requireAuth, rejecting callers whose token isn't flagged as an admin.
+func (s *Server) requireAdmin(next apiHandler) apiHandler {
+	return func(w http.ResponseWriter, r *http.Request) *APIError {
+		if !isAdminFromContext(r.Context()) {
+			return errForbidden("admin access required")
+		}
+		return next(w, r)
+	}
+}
+
+// loginHandler verifies a username/password against the stored bcrypt hash
+// and issues an access and refresh token pair.
+func (s *Server) loginHandler(w http.ResponseWriter, r *http.Request) *APIError {
+	params, err := getDataParams(r)
+	if err != nil {
+		return errBadRequest("invalid POST params")
+	}
+
+	username := params["username"]
+	password := params["password"]
+	if username == "" || password == "" {
+		return errBadRequest("username and password are required")
+	}
+
+	req, err := s.db.connect()
+	if err != nil {
+		return errDatabase(err)
+	}
+
+	user, err := req.GetUserByName(username)
+	if err != nil {
+		return errUnauthorized("invalid username or password")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return errUnauthorized("invalid username or password")
+	}
+
+	accessToken, err := s.issueToken(user.ID, user.IsAdmin, tokenTypeAccess, accessTokenTTL)
+	if err != nil {
+		return newAPIError(http.StatusInternalServerError, "token_error", "failed to issue token", err)
+	}
+	refreshToken, err := s.issueToken(user.ID, user.IsAdmin, tokenTypeRefresh, refreshTokenTTL)
+	if err != nil {
+		return newAPIError(http.StatusInternalServerError, "token_error", "failed to issue token", err)
+	}
+
+	json, err := toJSON(map[string]string{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+	})
+	if err != nil {
+		return errJSON(err)
+	}
+
+	httpRespond(w, r, json, http.StatusOK)
+	return nil
+}
+
+// refreshHandler exchanges a valid refresh token for a new access token.
+func (s *Server) refreshHandler(w http.ResponseWriter, r *http.Request) *APIError {
+	params, err := getDataParams(r)
+	if err != nil {
+		return errBadRequest("invalid POST params")
+	}
+
+	claims, err := s.parseToken(params["refresh_token"], tokenTypeRefresh)
+	if err != nil {
+		return errUnauthorized("invalid or expired refresh token")
+	}
+
+	accessToken, err := s.issueToken(claims.UserID, claims.IsAdmin, tokenTypeAccess, accessTokenTTL)
+	if err != nil {
+		return newAPIError(http.StatusInternalServerError, "token_error", "failed to issue token", err)
+	}
+
+	json, err := toJSON(map[string]string{"access_token": accessToken})
+	if err != nil {
+		return errJSON(err)
+	}
+
+	httpRespond(w, r, json, http.StatusOK)
+	return nil
+}
+
+// requireMatchingUser returns an error if the authenticated user (from the
+// request context) doesn't match userID, e.g. the user_id a write targets.
+func requireMatchingUser(ctx context.Context, userID int) *APIError {
+	authUserID, ok := userIDFromContext(ctx)
+	if !ok {
+		return errUnauthorized("missing authenticated user")
+	}
+	if authUserID != userID {
+		return errForbidden("cannot act on behalf of another user")
+	}
+	return nil
+}
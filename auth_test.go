@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestAuthServer() *Server {
+	return &Server{cfg: Config{JWTSecret: "test-secret"}}
+}
+
+func TestParseToken_RejectsWrongTokenType(t *testing.T) {
+	s := newTestAuthServer()
+
+	accessToken, err := s.issueToken(1, false, tokenTypeAccess, accessTokenTTL)
+	if err != nil {
+		t.Fatalf("issueToken: %v", err)
+	}
+
+	if _, err := s.parseToken(accessToken, tokenTypeRefresh); err == nil {
+		t.Error("parseToken accepted an access token where a refresh token was required")
+	}
+	if _, err := s.parseToken(accessToken, tokenTypeAccess); err != nil {
+		t.Errorf("parseToken rejected a valid access token: %v", err)
+	}
+}
+
+func TestRequireAuth_RejectsRefreshTokenAsBearer(t *testing.T) {
+	s := newTestAuthServer()
+
+	refreshToken, err := s.issueToken(1, false, tokenTypeRefresh, refreshTokenTTL)
+	if err != nil {
+		t.Fatalf("issueToken: %v", err)
+	}
+
+	handler := s.requireAuth(func(w http.ResponseWriter, r *http.Request) *APIError {
+		t.Error("handler should not be reached with a refresh token as the bearer token")
+		return nil
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/recommend", nil)
+	r.Header.Set("Authorization", "Bearer "+refreshToken)
+	w := httptest.NewRecorder()
+
+	if apiErr := handler(w, r); apiErr == nil {
+		t.Error("requireAuth() did not reject a refresh token used as a bearer token")
+	}
+}
+
+func TestRequireMatchingUser(t *testing.T) {
+	ctx := context.WithValue(context.Background(), authUserIDKey, 5)
+
+	if apiErr := requireMatchingUser(ctx, 5); apiErr != nil {
+		t.Errorf("requireMatchingUser rejected a write by the authenticated user themselves: %v", apiErr)
+	}
+	if apiErr := requireMatchingUser(ctx, 6); apiErr == nil {
+		t.Error("requireMatchingUser allowed a write on behalf of another user")
+	}
+}
+
+func TestRequireAdmin_RejectsNonAdmin(t *testing.T) {
+	s := newTestAuthServer()
+
+	handler := s.requireAdmin(func(w http.ResponseWriter, r *http.Request) *APIError {
+		t.Error("handler should not be reached by a non-admin caller")
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), authIsAdminKey, false)
+	r := httptest.NewRequest(http.MethodGet, "/jobs", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	if apiErr := handler(w, r); apiErr == nil {
+		t.Error("requireAdmin() did not reject a non-admin caller")
+	}
+}
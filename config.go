@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config holds every value previously hard-coded in main, loaded from the
+// environment so deployments can override them without a rebuild.
+type Config struct {
+	Host string
+	Port int
+
+	RootPath string
+	DBPath   string
+
+	JWTSecret  string
+	TMDbAPIKey string
+
+	WorkerConcurrency  int
+	SVDRebuildInterval time.Duration
+}
+
+// LoadConfig reads Config from environment variables, falling back to the
+// project's existing defaults where a variable isn't set. JWT_SECRET has no
+// default: a deployment that forgets to set it would otherwise silently sign
+// tokens with a secret baked into the public source, forgeable by anyone.
+func LoadConfig() (Config, error) {
+	rootPath := envOrDefault("ROOT_PATH", os.Getenv("GOPATH")+"/src/github.com/jemgunay/film-recommend")
+
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		return Config{}, fmt.Errorf("JWT_SECRET must be set")
+	}
+
+	return Config{
+		Host:     envOrDefault("HOST", "127.0.0.1"),
+		Port:     envIntOrDefault("PORT", 8006),
+		RootPath: rootPath,
+		DBPath:   envOrDefault("DB_PATH", rootPath+"/film-recommend.db"),
+
+		JWTSecret:  jwtSecret,
+		TMDbAPIKey: os.Getenv("TMDB_API_KEY"),
+
+		WorkerConcurrency:  envIntOrDefault("WORKER_CONCURRENCY", 4),
+		SVDRebuildInterval: envDurationOrDefault("SVD_REBUILD_INTERVAL", time.Hour),
+	}, nil
+}
+
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envIntOrDefault(key string, fallback int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func envDurationOrDefault(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return d
+}
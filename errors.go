@@ -0,0 +1,65 @@
+package main
+
+import "net/http"
+
+// APIError is a handler-level error that carries enough information to be
+// rendered as a structured JSON response and logged consistently.
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Status  int    `json:"-"`
+	Cause   error  `json:"-"`
+}
+
+// Error satisfies the error interface so an *APIError can be logged like
+// any other error.
+func (e *APIError) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+// newAPIError builds an APIError, optionally wrapping a lower-level cause
+// that is logged but never exposed to the client.
+func newAPIError(status int, code, message string, cause error) *APIError {
+	return &APIError{Code: code, Message: message, Status: status, Cause: cause}
+}
+
+// errDatabase is returned whenever a DB call fails; the cause is logged but
+// not leaked to the client.
+func errDatabase(cause error) *APIError {
+	return newAPIError(http.StatusInternalServerError, "db_error", "a database error occurred", cause)
+}
+
+// errJSON is returned when a result fails to marshal to JSON.
+func errJSON(cause error) *APIError {
+	return newAPIError(http.StatusInternalServerError, "json_error", "failed to encode response", cause)
+}
+
+// errBadRequest is returned for invalid or missing request parameters.
+func errBadRequest(message string) *APIError {
+	return newAPIError(http.StatusBadRequest, "bad_request", message, nil)
+}
+
+// errUnauthorized is returned when a request has no, or an invalid, JWT.
+func errUnauthorized(message string) *APIError {
+	return newAPIError(http.StatusUnauthorized, "unauthorized", message, nil)
+}
+
+// errForbidden is returned when an authenticated user acts on another
+// user's data.
+func errForbidden(message string) *APIError {
+	return newAPIError(http.StatusForbidden, "forbidden", message, nil)
+}
+
+// errorEnvelope is the JSON body returned to clients for any APIError.
+type errorEnvelope struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+}
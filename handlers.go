@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Get home HTML.
+func (s *Server) searchHandler(w http.ResponseWriter, r *http.Request) *APIError {
+	// get users from DB for drop down
+	req, err := s.db.connect()
+	if err != nil {
+		return errDatabase(err)
+	}
+
+	templateData := struct {
+		Users []User
+	}{
+		*req.GetUsers(),
+	}
+
+	htmlResult := completeTemplate("/dynamic/search.html", templateData)
+
+	httpRespond(w, r, htmlResult, http.StatusOK)
+	return nil
+}
+
+// Get a recommendation for a specific user.
+func (s *Server) recommendHandler(w http.ResponseWriter, r *http.Request) *APIError {
+	// parse params
+	params := getURLParams(r)
+
+	if params["user_id"] == "" {
+		return errBadRequest("no user_id provided")
+	}
+
+	userID, err := strconv.Atoi(params["user_id"])
+	if err != nil {
+		return errBadRequest("invalid user_id provided")
+	}
+
+	numResults, _ := strconv.Atoi(params["num_results"])
+	weights := parseAlgoWeights(params["weights"])
+
+	if algo := params["algo"]; algo != "" && algo != "blend" {
+		if _, ok := s.recommender.algorithms[algo]; !ok {
+			return errBadRequest("unknown algo provided")
+		}
+	}
+
+	result, err := s.recommender.recommend(userID, numResults, params["algo"], weights)
+	if err != nil {
+		return errDatabase(err)
+	}
+
+	json, err := toJSON(result)
+	if err != nil {
+		return errJSON(err)
+	}
+
+	httpRespond(w, r, json, http.StatusOK)
+	return nil
+}
+
+// Get home HTML.
+func (s *Server) watchedHandler(w http.ResponseWriter, r *http.Request) *APIError {
+	switch r.Method {
+	// get a user's watched list
+	case http.MethodGet:
+		params := getURLParams(r)
+
+		// perform DB request
+		req, err := s.db.connect()
+		if err != nil {
+			return errDatabase(err)
+		}
+
+		var resultData interface{}
+
+		switch {
+		// get watched lists for all users
+		case params["user_id"] != "":
+			resultData = req.GetWatchedByUserID(params["user_id"])
+
+		default:
+			watchedResults := req.GetAllWatchedListData()
+
+			// map[userID]map[filmID]rating
+			watchedLists := make(map[string]map[int]float64)
+
+			for _, record := range *watchedResults {
+				userIDStr := fmt.Sprint(record.UserID)
+
+				// check if user has been found yet
+				if _, ok := watchedLists[userIDStr]; !ok {
+					watchedLists[userIDStr] = make(map[int]float64)
+				}
+
+				// add film & rating record to user
+				m := watchedLists[userIDStr]
+				m[record.FilmID] = float64(record.Rating)
+				watchedLists[userIDStr] = m
+			}
+			resultData = watchedLists
+		}
+
+		// parse response to JSON
+		json, err := toJSON(resultData)
+		if err != nil {
+			return errJSON(err)
+		}
+
+		httpRespond(w, r, json, http.StatusOK)
+
+	// add a film to a users' watched list
+	case http.MethodPost:
+		params, err := getDataParams(r)
+		if err != nil {
+			return errBadRequest("invalid POST params")
+		}
+
+		fmt.Println(params)
+
+		// enforce reqired params
+		requiredParams := []string{"user_id", "film_id", "rating"}
+		for _, param := range requiredParams {
+			if params[param] == "" {
+				return errBadRequest("no " + param + " provided")
+			}
+		}
+
+		// parse to ints
+		userID, _ := strconv.Atoi(params["user_id"])
+		filmID, _ := strconv.Atoi(params["film_id"])
+		rating, _ := strconv.Atoi(params["rating"])
+
+		if apiErr := requireMatchingUser(r.Context(), userID); apiErr != nil {
+			return apiErr
+		}
+
+		// perform DB request
+		req, err := s.db.connect()
+		if err != nil {
+			return errDatabase(err)
+		}
+
+		err = req.AddFilmToWatchedList(userID, filmID, rating)
+		if err != nil {
+			return errDatabase(err)
+		}
+
+		// enqueue metadata enrichment rather than fetching it inline
+		if _, err := s.jobWorker.Enqueue("enrich_film", enrichFilmPayload{FilmID: filmID}); err != nil {
+			fmt.Println(err)
+		}
+
+		// notify other household members that a new rating has landed
+		s.hub.Publish(0, "film_watched", map[string]int{
+			"user_id": userID,
+			"film_id": filmID,
+			"rating":  rating,
+		})
+
+		httpRespond(w, r, "film successfully added", http.StatusOK)
+	}
+
+	return nil
+}
+
+// Get all user data.
+func (s *Server) userHandler(w http.ResponseWriter, r *http.Request) *APIError {
+	params := getURLParams(r)
+
+	// perform DB request
+	req, err := s.db.connect()
+	if err != nil {
+		return errDatabase(err)
+	}
+
+	var resultData interface{}
+
+	switch {
+	// get user by user name
+	case params["user"] != "":
+		resultData, err = req.GetUserByName(params["user"])
+		if err != nil {
+			return errDatabase(err)
+		}
+
+	// get user by user ID
+	case params["user_id"] != "":
+		resultData, err = req.GetUserByID(params["user_id"])
+		if err != nil {
+			return errDatabase(err)
+		}
+	}
+
+	json, err := toJSON(resultData)
+	if err != nil {
+		return errJSON(err)
+	}
+
+	httpRespond(w, r, json, http.StatusOK)
+	return nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteWait      = 10 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsPingPeriod     = (wsPongWait * 9) / 10
+	wsSendBufferSize = 16
+)
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// Event is a message pushed to subscribed clients.
+type Event struct {
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload"`
+}
+
+// broadcastMessage pairs an Event with the user it is destined for; a zero
+// userID fans the event out to every connected client.
+type broadcastMessage struct {
+	userID int
+	event  Event
+}
+
+// client is a single authenticated websocket connection, following the Go
+// Programming Blueprints chat-hub pattern: a buffered send channel decouples
+// the hub from slow or blocked connections.
+type client struct {
+	userID int
+	conn   *websocket.Conn
+	send   chan []byte
+}
+
+// Hub fans out events to the websocket clients of the user(s) they target.
+type Hub struct {
+	mu      sync.Mutex
+	clients map[int]map[*client]bool
+
+	register   chan *client
+	unregister chan *client
+	broadcast  chan broadcastMessage
+}
+
+// newHub creates a Hub; call Run to start its event loop.
+func newHub() *Hub {
+	return &Hub{
+		clients:    make(map[int]map[*client]bool),
+		register:   make(chan *client),
+		unregister: make(chan *client),
+		broadcast:  make(chan broadcastMessage, 64),
+	}
+}
+
+// Run processes register/unregister/broadcast until the channels are
+// abandoned; it is intended to be run in its own goroutine for the lifetime
+// of the process.
+func (h *Hub) Run() {
+	for {
+		select {
+		case c := <-h.register:
+			h.mu.Lock()
+			if h.clients[c.userID] == nil {
+				h.clients[c.userID] = make(map[*client]bool)
+			}
+			h.clients[c.userID][c] = true
+			h.mu.Unlock()
+
+		case c := <-h.unregister:
+			h.mu.Lock()
+			if _, ok := h.clients[c.userID][c]; ok {
+				delete(h.clients[c.userID], c)
+				close(c.send)
+			}
+			h.mu.Unlock()
+
+		case msg := <-h.broadcast:
+			body, err := json.Marshal(msg.event)
+			if err != nil {
+				continue
+			}
+
+			h.mu.Lock()
+			for userID, conns := range h.clients {
+				if msg.userID != 0 && msg.userID != userID {
+					continue
+				}
+				for c := range conns {
+					select {
+					case c.send <- body:
+					default:
+						// client isn't draining its send buffer; drop it
+						delete(conns, c)
+						close(c.send)
+					}
+				}
+			}
+			h.mu.Unlock()
+		}
+	}
+}
+
+// Publish fans eventType/payload out to userID's connections, or to every
+// connected client if userID is 0.
+func (h *Hub) Publish(userID int, eventType string, payload interface{}) {
+	h.broadcast <- broadcastMessage{userID: userID, event: Event{Type: eventType, Payload: payload}}
+}
+
+// wsHandler upgrades the connection to a websocket after authenticating the
+// caller's JWT, then registers it with the hub.
+func (s *Server) wsHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	claims, err := s.parseToken(token, tokenTypeAccess)
+	if err != nil {
+		http.Error(w, "invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Error("websocket upgrade failed", "cause", err)
+		return
+	}
+
+	c := &client{userID: claims.UserID, conn: conn, send: make(chan []byte, wsSendBufferSize)}
+	s.hub.register <- c
+
+	go c.writePump()
+	go c.readPump(s.hub)
+}
+
+// readPump discards any client-sent messages (this is a push-only channel)
+// but keeps the pong/deadline handshake alive, unregistering on any error.
+func (c *client) readPump(hub *Hub) {
+	defer func() {
+		hub.unregister <- c
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		return nil
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump relays queued events to the client and sends periodic pings,
+// enforcing write deadlines throughout.
+func (c *client) writePump() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case body, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, body); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
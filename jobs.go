@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// enrichFilmPayload is the job payload used to fetch and persist TMDb
+// metadata for a single film.
+type enrichFilmPayload struct {
+	FilmID int `json:"film_id"`
+}
+
+// enrichFilmJobHandler fetches metadata for a film from TMDb and persists it
+// against the film record so the recommender can use it as a content feature.
+func (s *Server) enrichFilmJobHandler(payload json.RawMessage) error {
+	var p enrichFilmPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+
+	meta, err := s.tmdbClient.FetchFilm(p.FilmID)
+	if err != nil {
+		return err
+	}
+
+	req, err := s.db.connect()
+	if err != nil {
+		return err
+	}
+
+	if err := req.UpdateFilmMetadata(p.FilmID, meta); err != nil {
+		return err
+	}
+
+	// notify anyone who has already watched this film that its metadata is ready
+	for _, userID := range req.GetUserIDsByFilmID(p.FilmID) {
+		s.hub.Publish(userID, "enrichment_complete", map[string]int{"film_id": p.FilmID})
+	}
+
+	return nil
+}
+
+// Admin endpoint to list, retry or cancel background jobs. Gated by
+// requireAdmin, since any authenticated user would otherwise be able to see
+// and control every household's background jobs.
+func (s *Server) jobsHandler(w http.ResponseWriter, r *http.Request) *APIError {
+	switch r.Method {
+	case http.MethodGet:
+		jobs, err := s.jobWorker.ListJobs()
+		if err != nil {
+			return errDatabase(err)
+		}
+
+		result, err := toJSON(jobs)
+		if err != nil {
+			return errJSON(err)
+		}
+
+		httpRespond(w, r, result, http.StatusOK)
+
+	case http.MethodPost:
+		params, err := getDataParams(r)
+		if err != nil {
+			return errBadRequest("invalid POST params")
+		}
+
+		id, err := strconv.ParseInt(params["id"], 10, 64)
+		if err != nil {
+			return errBadRequest("invalid id provided")
+		}
+
+		switch params["action"] {
+		case "retry":
+			err = s.jobWorker.RetryJob(id)
+		case "cancel":
+			err = s.jobWorker.CancelJob(id)
+		default:
+			return errBadRequest("invalid action provided")
+		}
+
+		if err != nil {
+			return errBadRequest(err.Error())
+		}
+
+		httpRespond(w, r, "ok", http.StatusOK)
+	}
+
+	return nil
+}
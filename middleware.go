@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// apiHandler is the signature used by every route: handlers return an
+// *APIError instead of writing error responses themselves, so the
+// middleware can render and log them consistently.
+type apiHandler func(w http.ResponseWriter, r *http.Request) *APIError
+
+// statusCounters tracks how many responses have been served per HTTP
+// status code.
+var statusCounters = struct {
+	mu     sync.Mutex
+	counts map[int]int
+}{counts: make(map[int]int)}
+
+func incrementStatusCounter(status int) {
+	statusCounters.mu.Lock()
+	defer statusCounters.mu.Unlock()
+	statusCounters.counts[status]++
+}
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// withAPIMiddleware wraps an apiHandler with request-scoped logging and
+// converts any returned *APIError into the standard JSON error envelope.
+func (s *Server) withAPIMiddleware(handler apiHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := newRequestID()
+		r = r.WithContext(context.WithValue(r.Context(), requestIDKey, requestID))
+		w.Header().Set("X-Request-ID", requestID)
+
+		apiErr := handler(w, r)
+
+		status := http.StatusOK
+		if apiErr != nil {
+			status = apiErr.Status
+			writeAPIError(w, requestID, apiErr)
+		}
+
+		incrementStatusCounter(status)
+
+		s.logger.Info("request",
+			"request_id", requestID,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+		)
+
+		if apiErr != nil && apiErr.Cause != nil {
+			s.logger.Error("request failed", "request_id", requestID, "cause", apiErr.Cause)
+		}
+	}
+}
+
+// writeAPIError renders the standard {"error": {...}} JSON envelope.
+func writeAPIError(w http.ResponseWriter, requestID string, apiErr *APIError) {
+	envelope := errorEnvelope{Error: errorBody{
+		Code:      apiErr.Code,
+		Message:   apiErr.Message,
+		RequestID: requestID,
+	}}
+
+	body, err := toJSON(envelope)
+	if err != nil {
+		http.Error(w, apiErr.Message, apiErr.Status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.Status)
+	fmt.Fprint(w, body)
+}
+
+// newRequestID returns a short random hex identifier for correlating logs
+// with a single request.
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Recommendation is a single scored film suggestion.
+type Recommendation struct {
+	FilmID int     `json:"film_id"`
+	Score  float64 `json:"score"`
+}
+
+// Algorithm produces scored film recommendations for a user from the full
+// watched/ratings matrix.
+type Algorithm interface {
+	// Name identifies the algorithm, used for the ?algo= query param.
+	Name() string
+	// Score returns a score (higher is better) for the unwatched films it can
+	// rate for userID; films it has no signal for are omitted.
+	Score(watched map[string]map[int]float64, userID int) (map[int]float64, error)
+}
+
+// defaultWeight is the blend weight used for an algorithm that wasn't given
+// an explicit weight via the ?weights= query param.
+const defaultWeight = 1.0
+
+// Recommender selects between several pluggable recommendation algorithms.
+type Recommender struct {
+	db         DBInstance
+	algorithms map[string]Algorithm
+}
+
+// NewRecommender builds a Recommender with the default, item-item
+// collaborative filtering, SVD and content-based algorithms registered.
+// svdModelPath is where the SVD factorisation is persisted between rebuilds.
+func NewRecommender(db DBInstance, svdModelPath string) Recommender {
+	svd := &svdAlgorithm{rank: 20, oversampling: 10, modelPath: svdModelPath}
+	// best effort: fall back to an on-demand rebuild if no model is cached yet
+	_ = svd.load()
+
+	return Recommender{
+		db: db,
+		algorithms: map[string]Algorithm{
+			"default": &averageRatingAlgorithm{},
+			"itemcf":  &itemCFAlgorithm{},
+			"svd":     svd,
+			"content": &contentBasedAlgorithm{},
+		},
+	}
+}
+
+// recommend returns the top numResults unwatched films for userID, scored by
+// the named algorithm. algo may be "default", "itemcf", "svd", "content", or
+// "blend" to linearly combine every registered algorithm using weights.
+func (r Recommender) recommend(userID, numResults int, algo string, weights map[string]float64) ([]Recommendation, error) {
+	if algo == "" {
+		algo = "default"
+	}
+
+	watched, err := r.loadWatchedMatrix()
+	if err != nil {
+		return nil, err
+	}
+
+	if algo == "content" || algo == "blend" {
+		if err := r.refreshContentMetadata(); err != nil {
+			return nil, err
+		}
+	}
+
+	var scores map[int]float64
+
+	if algo == "blend" {
+		scores, err = r.blend(watched, userID, weights)
+	} else {
+		alg, ok := r.algorithms[algo]
+		if !ok {
+			return nil, fmt.Errorf("unknown algorithm %q", algo)
+		}
+		scores, err = alg.Score(watched, userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("score films: %w", err)
+	}
+
+	return topN(scores, numResults), nil
+}
+
+// loadWatchedMatrix fetches every watched record and shapes it into
+// map[userID]map[filmID]rating for use by the recommendation algorithms.
+func (r Recommender) loadWatchedMatrix() (map[string]map[int]float64, error) {
+	req, err := r.db.connect()
+	if err != nil {
+		return nil, fmt.Errorf("connect to DB: %w", err)
+	}
+	watchedResults := req.GetAllWatchedListData()
+
+	watched := make(map[string]map[int]float64)
+	for _, record := range *watchedResults {
+		userIDStr := fmt.Sprint(record.UserID)
+		if _, ok := watched[userIDStr]; !ok {
+			watched[userIDStr] = make(map[int]float64)
+		}
+		watched[userIDStr][record.FilmID] = float64(record.Rating)
+	}
+
+	return watched, nil
+}
+
+// refreshContentMetadata fetches the latest TMDb-enriched film metadata and
+// hands it to the content-based algorithm, if registered.
+func (r Recommender) refreshContentMetadata() error {
+	content, ok := r.algorithms["content"].(*contentBasedAlgorithm)
+	if !ok {
+		return nil
+	}
+
+	req, err := r.db.connect()
+	if err != nil {
+		return fmt.Errorf("connect to DB: %w", err)
+	}
+
+	content.setMetadata(req.GetAllFilmMetadata())
+	return nil
+}
+
+// RebuildSVD recomputes and persists the SVD latent factor model from the
+// latest ratings data. Intended to be called periodically on a schedule.
+func (r Recommender) RebuildSVD() error {
+	svd, ok := r.algorithms["svd"].(*svdAlgorithm)
+	if !ok {
+		return fmt.Errorf("svd algorithm not registered")
+	}
+
+	watched, err := r.loadWatchedMatrix()
+	if err != nil {
+		return err
+	}
+
+	return svd.Rebuild(watched)
+}
+
+// blend linearly combines the score of every registered algorithm, weighting
+// each by weights[name] (defaultWeight if unspecified).
+func (r Recommender) blend(watched map[string]map[int]float64, userID int, weights map[string]float64) (map[int]float64, error) {
+	combined := make(map[int]float64)
+
+	for name, alg := range r.algorithms {
+		weight, ok := weights[name]
+		if !ok {
+			weight = defaultWeight
+		}
+		if weight == 0 {
+			continue
+		}
+
+		scores, err := alg.Score(watched, userID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for filmID, score := range scores {
+			combined[filmID] += score * weight
+		}
+	}
+
+	return combined, nil
+}
+
+// parseAlgoWeights parses a "?weights=itemcf:0.3,svd:0.7" style query param
+// into a per-algorithm weight map for blend mode. Malformed entries are
+// skipped.
+func parseAlgoWeights(raw string) map[string]float64 {
+	weights := make(map[string]float64)
+	if raw == "" {
+		return weights
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		weight, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			continue
+		}
+		weights[parts[0]] = weight
+	}
+
+	return weights
+}
+
+// topN sorts scores descending and returns the top n as Recommendations.
+func topN(scores map[int]float64, n int) []Recommendation {
+	results := make([]Recommendation, 0, len(scores))
+	for filmID, score := range scores {
+		results = append(results, Recommendation{FilmID: filmID, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	if n > 0 && n < len(results) {
+		results = results[:n]
+	}
+	return results
+}
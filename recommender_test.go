@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+// fakeAlgorithm returns a fixed score for every film, letting blend tests
+// assert on the combined weighting without touching the DB.
+type fakeAlgorithm struct {
+	name  string
+	score float64
+}
+
+func (f *fakeAlgorithm) Name() string { return f.name }
+
+func (f *fakeAlgorithm) Score(_ map[string]map[int]float64, _ int) (map[int]float64, error) {
+	return map[int]float64{1: f.score}, nil
+}
+
+func TestRecommenderBlend_WeightsCombineLinearly(t *testing.T) {
+	r := Recommender{
+		algorithms: map[string]Algorithm{
+			"a": &fakeAlgorithm{name: "a", score: 2},
+			"b": &fakeAlgorithm{name: "b", score: 4},
+		},
+	}
+
+	scores, err := r.blend(nil, 1, map[string]float64{"a": 0.5, "b": 0.25})
+	if err != nil {
+		t.Fatalf("blend returned error: %v", err)
+	}
+
+	want := 2*0.5 + 4*0.25
+	if got := scores[1]; got != want {
+		t.Errorf("blend() score = %v, want %v", got, want)
+	}
+}
+
+func TestRecommenderBlend_ZeroWeightExcludesAlgorithm(t *testing.T) {
+	r := Recommender{
+		algorithms: map[string]Algorithm{
+			"a": &fakeAlgorithm{name: "a", score: 100},
+		},
+	}
+
+	scores, err := r.blend(nil, 1, map[string]float64{"a": 0})
+	if err != nil {
+		t.Fatalf("blend returned error: %v", err)
+	}
+	if _, ok := scores[1]; ok {
+		t.Errorf("blend() included a zero-weighted algorithm's score")
+	}
+}
+
+func TestTopN(t *testing.T) {
+	scores := map[int]float64{1: 1, 2: 5, 3: 3}
+
+	got := topN(scores, 2)
+	if len(got) != 2 {
+		t.Fatalf("topN() returned %d results, want 2", len(got))
+	}
+	if got[0].FilmID != 2 || got[1].FilmID != 3 {
+		t.Errorf("topN() = %+v, want films [2, 3] in descending score order", got)
+	}
+}
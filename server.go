@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jemgunay/film-recommend/tmdb"
+	"github.com/jemgunay/film-recommend/worker"
+)
+
+// Server holds every dependency a handler needs, replacing the package-level
+// dbInstance/recommender/jobWorker globals so the app can be constructed and
+// torn down cleanly (and tested without shared mutable state).
+type Server struct {
+	db          DBInstance
+	recommender Recommender
+	jobWorker   *worker.Worker
+	tmdbClient  *tmdb.Client
+	hub         *Hub
+	logger      *slog.Logger
+	cfg         Config
+}
+
+// NewServer wires up the DB, recommender and background job worker described
+// by cfg.
+func NewServer(cfg Config) (*Server, error) {
+	db := NewDBInstance(cfg.DBPath)
+
+	s := &Server{
+		db:          db,
+		recommender: NewRecommender(db, cfg.RootPath+"/svd_model.gob"),
+		tmdbClient:  tmdb.NewClient(cfg.TMDbAPIKey),
+		hub:         newHub(),
+		logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+		cfg:         cfg,
+	}
+
+	w, err := worker.NewWorker(s.db.DB(), cfg.WorkerConcurrency)
+	if err != nil {
+		return nil, fmt.Errorf("init job worker: %w", err)
+	}
+	s.jobWorker = w
+	s.jobWorker.RegisterHandler("enrich_film", s.enrichFilmJobHandler)
+
+	go s.hub.Run()
+
+	return s, nil
+}
+
+// Router builds the mux.Router with every route bound to this Server's
+// handler methods.
+func (s *Server) Router() *mux.Router {
+	router := mux.NewRouter()
+
+	router.HandleFunc("/", s.withAPIMiddleware(s.searchHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/users", s.withAPIMiddleware(s.userHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/watched", s.withAPIMiddleware(s.requireAuth(s.watchedHandler))).Methods(http.MethodGet, http.MethodPost)
+	router.HandleFunc("/recommend", s.withAPIMiddleware(s.requireAuth(s.recommendHandler))).Methods(http.MethodGet)
+	router.HandleFunc("/jobs", s.withAPIMiddleware(s.requireAuth(s.requireAdmin(s.jobsHandler)))).Methods(http.MethodGet, http.MethodPost)
+	router.HandleFunc("/login", s.withAPIMiddleware(s.loginHandler)).Methods(http.MethodPost)
+	router.HandleFunc("/refresh", s.withAPIMiddleware(s.refreshHandler)).Methods(http.MethodPost)
+	router.HandleFunc("/ws", s.wsHandler).Methods(http.MethodGet)
+
+	staticFileHandler := http.StripPrefix("/static/", http.FileServer(http.Dir(s.cfg.RootPath+"/static/")))
+	router.Handle(`/static/{rest:[a-zA-Z0-9=\-\/._]+}`, staticFileHandler)
+
+	return router
+}
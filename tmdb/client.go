@@ -0,0 +1,82 @@
+// Package tmdb provides a minimal client for fetching film metadata from
+// The Movie Database (TMDb) API.
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const baseURL = "https://api.themoviedb.org/3"
+
+// Metadata is the subset of TMDb film data the recommender cares about.
+type Metadata struct {
+	Title      string   `json:"title"`
+	Genres     []string `json:"genres"`
+	Cast       []string `json:"cast"`
+	PosterPath string   `json:"poster_path"`
+	Plot       string   `json:"overview"`
+}
+
+// Client fetches film metadata from TMDb.
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client authenticated with the given TMDb API key.
+func NewClient(apiKey string) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// FetchFilm retrieves metadata for the given TMDb film ID.
+func (c *Client) FetchFilm(filmID int) (Metadata, error) {
+	url := fmt.Sprintf("%s/movie/%d?api_key=%s&append_to_response=credits", baseURL, filmID, c.apiKey)
+
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("fetch film %d: %w", filmID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Metadata{}, fmt.Errorf("fetch film %d: unexpected status %d", filmID, resp.StatusCode)
+	}
+
+	var raw struct {
+		Title   string `json:"title"`
+		Genres  []struct {
+			Name string `json:"name"`
+		} `json:"genres"`
+		PosterPath string `json:"poster_path"`
+		Overview   string `json:"overview"`
+		Credits    struct {
+			Cast []struct {
+				Name string `json:"name"`
+			} `json:"cast"`
+		} `json:"credits"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return Metadata{}, fmt.Errorf("decode film %d: %w", filmID, err)
+	}
+
+	meta := Metadata{
+		Title:      raw.Title,
+		PosterPath: raw.PosterPath,
+		Plot:       raw.Overview,
+	}
+	for _, g := range raw.Genres {
+		meta.Genres = append(meta.Genres, g.Name)
+	}
+	for _, cast := range raw.Credits.Cast {
+		meta.Cast = append(meta.Cast, cast.Name)
+	}
+
+	return meta, nil
+}
@@ -0,0 +1,293 @@
+// Package worker implements a small persistent job queue used for background
+// work such as enriching films with metadata from an external movie database.
+package worker
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job statuses.
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+const maxAttempts = 5
+
+// Job is a single unit of background work.
+type Job struct {
+	ID        int64
+	Type      string
+	Payload   json.RawMessage
+	Status    string
+	Attempts  int
+	LastError string
+	NextRunAt time.Time
+	CreatedAt time.Time
+}
+
+// HandlerFunc processes the payload of a job of a particular type.
+type HandlerFunc func(payload json.RawMessage) error
+
+// Worker polls a persistent job table and dispatches jobs to registered
+// handlers with exponential backoff on failure.
+type Worker struct {
+	db          *sql.DB
+	concurrency int
+
+	mu       sync.Mutex
+	handlers map[string]HandlerFunc
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewWorker creates the jobs table if required and returns a Worker backed
+// by db.
+func NewWorker(db *sql.DB, concurrency int) (*Worker, error) {
+	const schema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	type TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NOT NULL DEFAULT '',
+	next_run_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);`
+
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("create jobs table: %w", err)
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &Worker{
+		db:          db,
+		concurrency: concurrency,
+		handlers:    make(map[string]HandlerFunc),
+		stopCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}, nil
+}
+
+// RegisterHandler associates a job type with the function used to process it.
+func (w *Worker) RegisterHandler(jobType string, fn HandlerFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers[jobType] = fn
+}
+
+// Enqueue inserts a new pending job and returns its ID.
+func (w *Worker) Enqueue(jobType string, payload interface{}) (int64, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	res, err := w.db.Exec(
+		`INSERT INTO jobs (type, payload, status, next_run_at) VALUES (?, ?, ?, ?)`,
+		jobType, body, StatusPending, time.Now(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert job: %w", err)
+	}
+
+	return res.LastInsertId()
+}
+
+// Start launches the configured number of worker goroutines that poll for
+// due jobs until Stop is called.
+func (w *Worker) Start() {
+	for i := 0; i < w.concurrency; i++ {
+		go w.loop()
+	}
+}
+
+// Stop signals all worker goroutines to finish their current job and exit.
+func (w *Worker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *Worker) loop() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+			w.runNext()
+		}
+	}
+}
+
+func (w *Worker) runNext() {
+	job, ok, err := w.claimNext()
+	if err != nil {
+		log.Printf("worker: claim next job: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	w.mu.Lock()
+	handler, known := w.handlers[job.Type]
+	w.mu.Unlock()
+
+	if !known {
+		w.markFailed(job, fmt.Errorf("no handler registered for job type %q", job.Type))
+		return
+	}
+
+	if err := handler(job.Payload); err != nil {
+		w.markFailed(job, err)
+		return
+	}
+
+	if _, err := w.db.Exec(`UPDATE jobs SET status = ? WHERE id = ?`, StatusDone, job.ID); err != nil {
+		log.Printf("worker: mark job %d done: %v", job.ID, err)
+	}
+}
+
+// claimNext atomically marks the next due job as running and returns it. The
+// UPDATE re-checks status = 'pending' in its WHERE clause, so when multiple
+// workers race on the same candidate row only one of them affects it -
+// concurrent workers can never claim, and therefore run, the same job twice.
+func (w *Worker) claimNext() (Job, bool, error) {
+	var candidateID int64
+	err := w.db.QueryRow(
+		`SELECT id FROM jobs WHERE status = ? AND next_run_at <= ? ORDER BY id LIMIT 1`,
+		StatusPending, time.Now(),
+	).Scan(&candidateID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Job{}, false, nil
+		}
+		return Job{}, false, err
+	}
+
+	res, err := w.db.Exec(
+		`UPDATE jobs SET status = ? WHERE id = ? AND status = ?`,
+		StatusRunning, candidateID, StatusPending,
+	)
+	if err != nil {
+		return Job{}, false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		// lost the race to another worker; try again next tick
+		return Job{}, false, err
+	}
+
+	row := w.db.QueryRow(
+		`SELECT id, type, payload, status, attempts, last_error, next_run_at, created_at
+		 FROM jobs WHERE id = ?`,
+		candidateID,
+	)
+
+	var job Job
+	if err := row.Scan(&job.ID, &job.Type, &job.Payload, &job.Status, &job.Attempts,
+		&job.LastError, &job.NextRunAt, &job.CreatedAt); err != nil {
+		return Job{}, false, err
+	}
+
+	return job, true, nil
+}
+
+// markFailed records the error, bumps the attempt count and schedules a
+// retry with exponential backoff, or gives up after maxAttempts.
+func (w *Worker) markFailed(job Job, cause error) {
+	job.Attempts++
+
+	status := StatusPending
+	if job.Attempts >= maxAttempts {
+		status = StatusFailed
+	}
+
+	nextRun := time.Now().Add(backoff(job.Attempts))
+
+	_, err := w.db.Exec(
+		`UPDATE jobs SET status = ?, attempts = ?, last_error = ?, next_run_at = ? WHERE id = ?`,
+		status, job.Attempts, cause.Error(), nextRun, job.ID,
+	)
+	if err != nil {
+		log.Printf("worker: mark job %d failed: %v", job.ID, err)
+	}
+}
+
+// backoff returns an exponential delay capped at 10 minutes.
+func backoff(attempt int) time.Duration {
+	d := time.Second * time.Duration(1<<uint(attempt))
+	if max := 10 * time.Minute; d > max {
+		d = max
+	}
+	return d
+}
+
+// ListJobs returns all jobs, most recently created first, for the admin endpoint.
+func (w *Worker) ListJobs() ([]Job, error) {
+	rows, err := w.db.Query(
+		`SELECT id, type, payload, status, attempts, last_error, next_run_at, created_at
+		 FROM jobs ORDER BY id DESC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		var job Job
+		if err := rows.Scan(&job.ID, &job.Type, &job.Payload, &job.Status, &job.Attempts,
+			&job.LastError, &job.NextRunAt, &job.CreatedAt); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// RetryJob resets a failed job back to pending so it is picked up again.
+func (w *Worker) RetryJob(id int64) error {
+	res, err := w.db.Exec(
+		`UPDATE jobs SET status = ?, next_run_at = ? WHERE id = ? AND status = ?`,
+		StatusPending, time.Now(), id, StatusFailed,
+	)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+// CancelJob removes a job that has not yet completed.
+func (w *Worker) CancelJob(id int64) error {
+	res, err := w.db.Exec(`DELETE FROM jobs WHERE id = ? AND status != ?`, id, StatusRunning)
+	if err != nil {
+		return err
+	}
+	return checkRowsAffected(res)
+}
+
+func checkRowsAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("job not found")
+	}
+	return nil
+}
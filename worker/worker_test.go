@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newTestWorker(t *testing.T) *Worker {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	// every new connection to ":memory:" opens its own empty database, so keep
+	// the pool at one connection to let concurrent callers share the jobs table
+	db.SetMaxOpenConns(1)
+
+	w, err := NewWorker(db, 1)
+	if err != nil {
+		t.Fatalf("NewWorker: %v", err)
+	}
+	return w
+}
+
+// TestClaimNext_ConcurrentWorkersDontDoubleClaim races many goroutines
+// against a single pending job; the atomic UPDATE ... WHERE status =
+// 'pending' in claimNext must let exactly one of them win.
+func TestClaimNext_ConcurrentWorkersDontDoubleClaim(t *testing.T) {
+	w := newTestWorker(t)
+
+	if _, err := w.Enqueue("enrich_film", map[string]int{"film_id": 1}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	const attempts = 20
+	var claimed int32
+	var wg sync.WaitGroup
+	wg.Add(attempts)
+
+	for i := 0; i < attempts; i++ {
+		go func() {
+			defer wg.Done()
+			_, ok, err := w.claimNext()
+			if err != nil {
+				t.Errorf("claimNext: %v", err)
+				return
+			}
+			if ok {
+				atomic.AddInt32(&claimed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if claimed != 1 {
+		t.Errorf("claimed = %d, want exactly 1 (job must not be claimed twice)", claimed)
+	}
+}
+
+func TestClaimNext_NoPendingJobs(t *testing.T) {
+	w := newTestWorker(t)
+
+	_, ok, err := w.claimNext()
+	if err != nil {
+		t.Fatalf("claimNext: %v", err)
+	}
+	if ok {
+		t.Error("claimNext() ok = true with no pending jobs enqueued")
+	}
+}